@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupInfo defines the parameters of a scheduled snapshot-chain backup
+// of a ZFSVolume to an external object store
+type BackupInfo struct {
+	// VolumeName is the ZFSVolume being backed up
+	VolumeName string `json:"volumeName"`
+
+	// PoolName is the pool in which the volume/dataset lives
+	PoolName string `json:"poolName"`
+
+	// OwnerNodeID is the node the backing volume lives on; the backup
+	// must be taken on this node
+	OwnerNodeID string `json:"ownerNodeID"`
+
+	// BackupTargetName names the BackupTarget this backup ships its
+	// snapshot streams to
+	BackupTargetName string `json:"backupTargetName"`
+
+	// RetentionCount is the number of snapshot-chain entries after which
+	// the chain is due for rebasing onto a new full backup: a RunBackup
+	// call with forceFull restarts the chain from a full send, and the
+	// superseded chain is then passed to GCSnapshots as a whole rather
+	// than destroyed piecemeal out from under a live chain. RunBackup and
+	// GCSnapshots do not read this field themselves; comparing
+	// Status.SnapshotChain against it and choosing forceFull and the
+	// snapshots to collect is left to their caller
+	RetentionCount int `json:"retentionCount"`
+}
+
+// ZFSBackupStatus defines the observed state of a ZFSBackup
+type ZFSBackupStatus struct {
+	State string `json:"state"`
+
+	// LastSnapshot is the most recently shipped backup snapshot, used as
+	// the base for the next incremental `zfs send -i`
+	LastSnapshot string `json:"lastSnapshot,omitempty"`
+
+	// SnapshotChain lists every backup snapshot shipped so far, oldest
+	// first, starting with the initial full send. A restore must replay
+	// the chain in order since every entry after the first is only an
+	// incremental stream relative to its predecessor.
+	SnapshotChain []string `json:"snapshotChain,omitempty"`
+
+	// ChangedPaths lists the paths zfs diff reported between the
+	// previous and current backup snapshot
+	ChangedPaths []string `json:"changedPaths,omitempty"`
+
+	// ChangedBytes is the total size of the files zfs diff reported as
+	// changed between the previous and current backup snapshot; paths
+	// that cannot be stat'ed (e.g. removed files) are not counted
+	ChangedBytes int64 `json:"changedBytes,omitempty"`
+}
+
+// ZFSBackup manages a chain of named snapshots on a ZFSVolume and ships
+// incremental `zfs send` streams between them to an external object store
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced
+type ZFSBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupInfo      `json:"spec"`
+	Status ZFSBackupStatus `json:"status,omitempty"`
+}
+
+// ZFSBackupList is a list of ZFSBackup resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ZFSBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ZFSBackup `json:"items"`
+}
+
+// RestoreInfo defines the parameters to reconstruct a ZFSVolume from a
+// ZFSBackup's stored snapshot chain
+type RestoreInfo struct {
+	// VolumeName is the ZFSVolume to reconstruct
+	VolumeName string `json:"volumeName"`
+
+	// PoolName is the pool the reconstructed volume/dataset is created in
+	PoolName string `json:"poolName"`
+
+	// OwnerNodeID is the node the restore is performed on
+	OwnerNodeID string `json:"ownerNodeID"`
+
+	// BackupName is the ZFSBackup whose snapshot chain is restored
+	BackupName string `json:"backupName"`
+}
+
+// ZFSRestoreStatus defines the observed state of a ZFSRestore
+type ZFSRestoreStatus struct {
+	State string `json:"state"`
+}
+
+// ZFSRestore reconstructs a ZFSVolume on a node from a ZFSBackup's stored
+// snapshot streams
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced
+type ZFSRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RestoreInfo      `json:"spec"`
+	Status ZFSRestoreStatus `json:"status,omitempty"`
+}
+
+// ZFSRestoreList is a list of ZFSRestore resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ZFSRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ZFSRestore `json:"items"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotInfo defines the parameters of a ZFS snapshot
+type SnapshotInfo struct {
+	// VolumeName is the ZFSVolume this snapshot is taken from
+	VolumeName string `json:"volumeName"`
+
+	// PoolName is the pool in which the volume/dataset lives
+	PoolName string `json:"poolName"`
+
+	// OwnerNodeID is the node where the backing volume is located,
+	// the snapshot must be reconciled on this node
+	OwnerNodeID string `json:"ownerNodeID"`
+}
+
+// ZFSSnapshotStatus defines the observed state of ZFSSnapshot
+type ZFSSnapshotStatus struct {
+	State string `json:"state"`
+}
+
+// ZFSSnapshot represents a point in time snapshot of a ZFSVolume
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced
+type ZFSSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotInfo      `json:"spec"`
+	Status ZFSSnapshotStatus `json:"status,omitempty"`
+}
+
+// ZFSSnapshotList is a list of ZFSSnapshot resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ZFSSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ZFSSnapshot `json:"items"`
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeInfo defines ZFS volume parameters for all modes in which
+// it can be created
+type VolumeInfo struct {
+	// OwnerNodeID is the Node ID where the volume should be created
+	OwnerNodeID string `json:"ownerNodeID"`
+
+	// PoolName is the pool in which the volume/dataset is created
+	PoolName string `json:"poolName"`
+
+	// Capacity of the volume
+	Capacity string `json:"capacity"`
+
+	// VolumeType is the type of the volume, dataset or zvol
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// ThinProvision is set to yes for thin provisioned volumes
+	ThinProvision string `json:"thinProvision,omitempty"`
+
+	// VolBlockSize is the block size for zvols
+	VolBlockSize string `json:"volBlockSize,omitempty"`
+
+	// RecordSize is the recordsize for datasets
+	RecordSize string `json:"recordsize,omitempty"`
+
+	// Dedup property of the volume
+	Dedup string `json:"dedup,omitempty"`
+
+	// Compression property of the volume
+	Compression string `json:"compression,omitempty"`
+
+	// Encryption property of the volume
+	Encryption string `json:"encryption,omitempty"`
+
+	// KeyLocation is the location of the encryption key
+	KeyLocation string `json:"keyLocation,omitempty"`
+
+	// KeyFormat is the format of the encryption key
+	KeyFormat string `json:"keyFormat,omitempty"`
+
+	// SnapshotName is the name of the ZFSSnapshot this volume should be
+	// cloned from. When set, CreateVolume clones the volume from the
+	// snapshot instead of creating it from scratch. SnapshotVolumeName
+	// must also be set, since a ZFS snapshot reference is
+	// pool/<SnapshotVolumeName>@<SnapshotName>, not just pool/<SnapshotName>.
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// SnapshotVolumeName is the name of the ZFSVolume the snapshot named
+	// by SnapshotName was taken from, i.e. the snapshot's
+	// spec.volumeName.
+	SnapshotVolumeName string `json:"snapshotVolumeName,omitempty"`
+
+	// KeyRef points at the Kubernetes Secret holding the encryption
+	// passphrase/raw key for this volume. When set, CreateVolume checks
+	// an already existing volume's keystatus and, if the key is
+	// unavailable (e.g. after a reboot), loads it from the keyReader it
+	// is given; a freshly created volume already has its key loaded by
+	// zfs create. CreateVolume does not fetch the Secret itself: the key
+	// comes from the reader its caller passes in.
+	KeyRef *KeyReference `json:"keyRef,omitempty"`
+}
+
+// KeyReference points at the key/passphrase for an encrypted ZFSVolume,
+// stored in a Kubernetes Secret in the same namespace as the ZFSVolume.
+type KeyReference struct {
+	// Name of the Secret holding the key
+	Name string `json:"name"`
+
+	// Key is the entry within the Secret's data that holds the
+	// passphrase/raw key
+	Key string `json:"key"`
+}
+
+// ZFSVolumeStatus defines the observed state of ZFSVolume
+type ZFSVolumeStatus struct {
+	State string `json:"state"`
+
+	// Used is the live "used" property reported by `zfs get`
+	Used string `json:"used,omitempty"`
+
+	// KeyStatus is the live "keystatus" property reported by `zfs get`,
+	// e.g. "available" or "unavailable", for encrypted volumes
+	KeyStatus string `json:"keystatus,omitempty"`
+}
+
+// ZFSVolume represents a ZFS volume/dataset provisioned on a node
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced
+type ZFSVolume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeInfo      `json:"spec"`
+	Status ZFSVolumeStatus `json:"status,omitempty"`
+}
+
+// ZFSVolumeList is a list of ZFSVolume resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ZFSVolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ZFSVolume `json:"items"`
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// migration phases for a ZFSVolumeMigration
+const (
+	MigrationPending     = "Pending"
+	MigrationFullSync    = "FullSync"
+	MigrationIncremental = "Incremental"
+	MigrationCutover     = "Cutover"
+	MigrationDone        = "Done"
+	MigrationFailed      = "Failed"
+)
+
+// MigrationInfo defines the parameters of a volume migration between nodes
+type MigrationInfo struct {
+	// VolumeName is the ZFSVolume being migrated
+	VolumeName string `json:"volumeName"`
+
+	// PoolName is the pool in which the volume/dataset lives, on both
+	// the source and the destination node
+	PoolName string `json:"poolName"`
+
+	// SourceNodeID is the node the volume is currently pinned to
+	SourceNodeID string `json:"sourceNodeID"`
+
+	// DestNodeID is the node the volume is being migrated to
+	DestNodeID string `json:"destNodeID"`
+}
+
+// ZFSVolumeMigrationStatus defines the observed state of a volume migration
+type ZFSVolumeMigrationStatus struct {
+	// Phase is one of Pending, FullSync, Incremental, Cutover, Done, Failed
+	Phase string `json:"phase"`
+
+	// LastSyncedSnapshot is the most recent snapshot that has been fully
+	// streamed to DestNodeID, used as the base for the next incremental
+	// send. This is reconciliation progress, not desired state, so it
+	// lives in Status rather than Spec.
+	LastSyncedSnapshot string `json:"lastSyncedSnapshot,omitempty"`
+}
+
+// ZFSVolumeMigration tracks a cross-node migration of a ZFSVolume via a
+// chain of `zfs send`/`zfs receive` snapshot streams
+//
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced
+type ZFSVolumeMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MigrationInfo            `json:"spec"`
+	Status ZFSVolumeMigrationStatus `json:"status,omitempty"`
+}
+
+// ZFSVolumeMigrationList is a list of ZFSVolumeMigration resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ZFSVolumeMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ZFSVolumeMigration `json:"items"`
+}
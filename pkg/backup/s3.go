@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Target is a BackupTarget backed by an S3-compatible bucket
+type S3Target struct {
+	Bucket   string
+	Uploader *s3manager.Uploader
+	Client   *s3.S3
+}
+
+// NewS3Target returns a BackupTarget that ships snapshot streams to the
+// given S3 bucket using sess
+func NewS3Target(bucket string, sess *session.Session) *S3Target {
+	return &S3Target{
+		Bucket:   bucket,
+		Uploader: s3manager.NewUploader(sess),
+		Client:   s3.New(sess),
+	}
+}
+
+// Upload streams reader to s3://bucket/key
+func (t *S3Target) Upload(key string, reader io.Reader) error {
+	_, err := t.Uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(key),
+		Body:   reader,
+	})
+	return err
+}
+
+// Download returns a reader for s3://bucket/key
+func (t *S3Target) Download(key string) (io.ReadCloser, error) {
+	out, err := t.Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// Delete removes s3://bucket/key
+func (t *S3Target) Delete(key string) error {
+	_, err := t.Client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup defines the pluggable destination a ZFSBackup ships its
+// `zfs send` snapshot streams to.
+package backup
+
+import "io"
+
+// BackupTarget is implemented by each supported object store so the
+// ZFSBackup controller can stay agnostic of where streams actually land.
+type BackupTarget interface {
+	// Upload streams reader to the object identified by key
+	Upload(key string, reader io.Reader) error
+
+	// Download returns a reader for the object identified by key; the
+	// caller must close it once done
+	Download(key string) (io.ReadCloser, error)
+
+	// Delete removes the object identified by key, used when garbage
+	// collecting backup snapshots outside the retention window
+	Delete(key string) error
+}
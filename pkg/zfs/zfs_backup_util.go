@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zfs
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+)
+
+// zfs diff command related constants
+const (
+	ZFSDiffArg = "diff"
+)
+
+// SnapshotDiff summarizes the changes zfs diff reports between two
+// consecutive backup snapshots of the same volume
+type SnapshotDiff struct {
+	ChangedPaths []string
+	ChangedBytes int64
+}
+
+// buildSnapshotDiffArgs returns the zfs diff command between two snapshots
+// of the same volume
+func buildSnapshotDiffArgs(vol *apis.ZFSVolume, prevSnap string, currSnap string) []string {
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	return []string{ZFSDiffArg, "-H", dataset + "@" + prevSnap, dataset + "@" + currSnap}
+}
+
+// GetSnapshotDiff runs zfs diff between prevSnap and currSnap on vol and
+// returns the changed paths together with the total size of the changed
+// files, for populating a ZFSBackup's status.changedPaths/changedBytes.
+func GetSnapshotDiff(vol *apis.ZFSVolume, prevSnap string, currSnap string) (*SnapshotDiff, error) {
+	args := buildSnapshotDiffArgs(vol, prevSnap, currSnap)
+
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not diff snapshots %v..%v cmd %v error: %s", prevSnap, currSnap, args, string(out),
+		)
+		return nil, err
+	}
+
+	diff := &SnapshotDiff{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		// zfs diff -H prints "change\ttype\tpath" for most change types
+		fields := strings.Split(line, "\t")
+		path := fields[len(fields)-1]
+		diff.ChangedPaths = append(diff.ChangedPaths, path)
+
+		if info, err := os.Stat(path); err == nil {
+			diff.ChangedBytes += info.Size()
+		}
+	}
+
+	return diff, nil
+}
+
+// buildBackupSnapshotDestroyArgs returns the zfs destroy command for a
+// single backup snapshot that has aged out of the retention window,
+// following the same argv-builder pattern as buildVolumeDestroyArgs.
+func buildBackupSnapshotDestroyArgs(vol *apis.ZFSVolume, snapName string) []string {
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	return []string{ZFSDestroyArg, dataset + "@" + snapName}
+}
+
+// DestroyBackupSnapshot removes a single aged-out backup snapshot from vol.
+// It is a no-op if the snapshot is already gone, so GCSnapshots can safely
+// retry a partially-completed garbage collection pass.
+func DestroyBackupSnapshot(vol *apis.ZFSVolume, snapName string) error {
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	if err := getVolume(dataset + "@" + snapName); err != nil {
+		return nil
+	}
+
+	args := buildBackupSnapshotDestroyArgs(vol, snapName)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not destroy backup snapshot %v@%v cmd %v error: %s", dataset, snapName, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("destroyed backup snapshot %s@%s", dataset, snapName)
+
+	return nil
+}
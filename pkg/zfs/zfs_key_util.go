@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zfs
+
+import (
+	"io"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+)
+
+// zfs key management command related constants
+const (
+	ZFSLoadKeyArg   = "load-key"
+	ZFSUnloadKeyArg = "unload-key"
+	ZFSChangeKeyArg = "change-key"
+)
+
+// buildLoadKeyArgs returns the zfs load-key command for the given volume,
+// reading the key from stdin (keylocation=prompt)
+func buildLoadKeyArgs(vol *apis.ZFSVolume) []string {
+	var ZFSVolArg []string
+
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	ZFSVolArg = append(ZFSVolArg, ZFSLoadKeyArg, volume)
+
+	return ZFSVolArg
+}
+
+// buildUnloadKeyArgs returns the zfs unload-key command for the given volume
+func buildUnloadKeyArgs(vol *apis.ZFSVolume) []string {
+	var ZFSVolArg []string
+
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	ZFSVolArg = append(ZFSVolArg, ZFSUnloadKeyArg, volume)
+
+	return ZFSVolArg
+}
+
+// buildChangeKeyArgs returns the zfs change-key command for the given
+// volume along with the new keylocation/keyformat properties
+func buildChangeKeyArgs(vol *apis.ZFSVolume, newKeyLocation string, newKeyFormat string) []string {
+	var ZFSVolArg []string
+
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	ZFSVolArg = append(ZFSVolArg, ZFSChangeKeyArg)
+	if len(newKeyLocation) != 0 {
+		ZFSVolArg = append(ZFSVolArg, "-o", "keylocation="+newKeyLocation)
+	}
+	if len(newKeyFormat) != 0 {
+		ZFSVolArg = append(ZFSVolArg, "-o", "keyformat="+newKeyFormat)
+	}
+	ZFSVolArg = append(ZFSVolArg, volume)
+
+	return ZFSVolArg
+}
+
+// LoadKey unlocks an encrypted dataset, e.g. after a node reboot left it
+// unavailable when keylocation is "prompt" or a file that wasn't mounted
+// yet. keyReader supplies the passphrase/raw key on stdin.
+func LoadKey(vol *apis.ZFSVolume, keyReader io.Reader) error {
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	args := buildLoadKeyArgs(vol)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	cmd.Stdin = keyReader
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not load key for volume %v cmd %v error: %s", volume, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("loaded key for volume %s", volume)
+
+	return nil
+}
+
+// UnloadKey removes the encryption key for a dataset from memory, locking
+// it until LoadKey is called again
+func UnloadKey(vol *apis.ZFSVolume) error {
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	args := buildUnloadKeyArgs(vol)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not unload key for volume %v cmd %v error: %s", volume, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("unloaded key for volume %s", volume)
+
+	return nil
+}
+
+// ChangeKey rotates the encryption key for a dataset to a new passphrase,
+// supplied on newKeyReader's stdin, optionally updating keylocation and
+// keyformat at the same time.
+func ChangeKey(vol *apis.ZFSVolume, newKeyLocation string, newKeyFormat string, newKeyReader io.Reader) error {
+	volume := vol.Spec.PoolName + "/" + vol.Name
+
+	args := buildChangeKeyArgs(vol, newKeyLocation, newKeyFormat)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	cmd.Stdin = newKeyReader
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not change key for volume %v cmd %v error: %s", volume, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("changed key for volume %s", volume)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zfs
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+)
+
+// zfs send/receive command related constants
+const (
+	ZFSSendArg    = "send"
+	ZFSReceiveArg = "receive"
+)
+
+// buildSendArgs returns the zfs send command for the given volume and
+// snapshot. If baseSnap is non-empty, an incremental stream relative to
+// baseSnap is requested with `-i`, otherwise a full stream is sent.
+func buildSendArgs(vol *apis.ZFSVolume, baseSnap string, snapName string) []string {
+	var ZFSArg []string
+
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+	snapshot := dataset + "@" + snapName
+
+	ZFSArg = append(ZFSArg, ZFSSendArg)
+	if len(baseSnap) != 0 {
+		ZFSArg = append(ZFSArg, "-i", dataset+"@"+baseSnap)
+	}
+	ZFSArg = append(ZFSArg, snapshot)
+
+	return ZFSArg
+}
+
+// buildReceiveArgs returns the zfs receive command for the given volume
+func buildReceiveArgs(vol *apis.ZFSVolume) []string {
+	var ZFSArg []string
+
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	ZFSArg = append(ZFSArg, ZFSReceiveArg, dataset)
+
+	return ZFSArg
+}
+
+// SendVolume streams a `zfs send` of the named snapshot for vol to writer.
+// If baseSnap is non-empty, only the incremental delta since baseSnap is
+// sent; otherwise a full stream is produced. The caller is expected to
+// forward writer over the node-to-node migration transport.
+func SendVolume(vol *apis.ZFSVolume, baseSnap string, snapName string, writer io.Writer) error {
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	args := buildSendArgs(vol, baseSnap, snapName)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	cmd.Stdout = writer
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	errOutput, _ := io.ReadAll(stderr)
+	if err := cmd.Wait(); err != nil {
+		logrus.Errorf(
+			"zfs: could not send volume %v cmd %v error: %s", dataset, args, string(errOutput),
+		)
+		return fmt.Errorf("zfs send failed for %s: %s: %w", dataset, string(errOutput), err)
+	}
+	logrus.Infof("sent volume %s snapshot %s", dataset, snapName)
+
+	return nil
+}
+
+// ReceiveVolume applies a `zfs send` stream read from reader onto the
+// dataset backing vol, creating or updating it on this node.
+func ReceiveVolume(vol *apis.ZFSVolume, reader io.Reader) error {
+	dataset := vol.Spec.PoolName + "/" + vol.Name
+
+	args := buildReceiveArgs(vol)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	cmd.Stdin = reader
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not receive volume %v cmd %v error: %s", dataset, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("received volume %s", dataset)
+
+	return nil
+}
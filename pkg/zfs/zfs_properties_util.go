@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zfs
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// zfs get command related constants
+const (
+	ZFSGetArg = "get"
+)
+
+// VolumeProperties holds the subset of `zfs get all` properties that
+// zfs-localpv cares about, parsed into typed fields so the reconciler can
+// compare them against a ZFSVolume's spec without re-parsing strings.
+type VolumeProperties struct {
+	Compression string
+	Dedup       string
+	RecordSize  string
+	Quota       string
+	Reservation string
+	Encryption  string
+	KeyStatus   string
+	Used        string
+	Referenced  string
+	Available   string
+}
+
+// GetVolumeProperties runs `zfs get -H -p -o property,value all` on volume
+// and returns every reported property as a property -> value map. Returns
+// an error if the volume does not exist or the command otherwise fails.
+func GetVolumeProperties(volume string) (map[string]string, error) {
+	args := []string{ZFSGetArg, "-H", "-p", "-o", "property,value", "all", volume}
+
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		properties[fields[0]] = fields[1]
+	}
+
+	return properties, nil
+}
+
+// ParseVolumeProperties converts the raw property map returned by
+// GetVolumeProperties into a VolumeProperties struct.
+func ParseVolumeProperties(properties map[string]string) *VolumeProperties {
+	return &VolumeProperties{
+		Compression: properties["compression"],
+		Dedup:       properties["dedup"],
+		RecordSize:  properties["recordsize"],
+		Quota:       properties["quota"],
+		Reservation: properties["reservation"],
+		Encryption:  properties["encryption"],
+		KeyStatus:   properties["keystatus"],
+		Used:        properties["used"],
+		Referenced:  properties["referenced"],
+		Available:   properties["available"],
+	}
+}
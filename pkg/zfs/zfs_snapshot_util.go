@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zfs
+
+import (
+	"io"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+)
+
+// snapshotDataset returns pool/vol@snap for the given ZFSSnapshot
+func snapshotDataset(snap *apis.ZFSSnapshot) string {
+	return snap.Spec.PoolName + "/" + snap.Spec.VolumeName + "@" + snap.Name
+}
+
+// buildSnapshotCreateArgs returns zfs snapshot command for the given
+// ZFSSnapshot as a string array
+func buildSnapshotCreateArgs(snap *apis.ZFSSnapshot) []string {
+	var ZFSSnapArg []string
+
+	ZFSSnapArg = append(ZFSSnapArg, ZFSSnapshotArg, snapshotDataset(snap))
+
+	return ZFSSnapArg
+}
+
+// buildSnapshotDestroyArgs returns zfs destroy command for the given
+// ZFSSnapshot as a string array
+func buildSnapshotDestroyArgs(snap *apis.ZFSSnapshot) []string {
+	var ZFSSnapArg []string
+
+	ZFSSnapArg = append(ZFSSnapArg, ZFSDestroyArg, snapshotDataset(snap))
+
+	return ZFSSnapArg
+}
+
+// buildSnapshotRollbackArgs returns zfs rollback command for the given
+// ZFSSnapshot as a string array
+func buildSnapshotRollbackArgs(snap *apis.ZFSSnapshot) []string {
+	var ZFSSnapArg []string
+
+	ZFSSnapArg = append(ZFSSnapArg, ZFSRollbackArg, snapshotDataset(snap))
+
+	return ZFSSnapArg
+}
+
+// buildCloneCreateArgs returns zfs clone command to create the volume
+// described by vol from the snapshot vol.Spec.SnapshotVolumeName@vol.Spec.SnapshotName
+func buildCloneCreateArgs(vol *apis.ZFSVolume) []string {
+	var ZFSVolArg []string
+
+	volume := vol.Spec.PoolName + "/" + vol.Name
+	snapshot := vol.Spec.PoolName + "/" + vol.Spec.SnapshotVolumeName + "@" + vol.Spec.SnapshotName
+
+	ZFSVolArg = append(ZFSVolArg, ZFSCloneArg, snapshot, volume)
+
+	return ZFSVolArg
+}
+
+// CreateSnapshot creates a snapshot for the given ZFSSnapshot object. It is
+// a no-op if the snapshot already exists, so a controller can safely call
+// it again on every reconcile without erroring on "dataset already exists".
+func CreateSnapshot(snap *apis.ZFSSnapshot) error {
+	dataset := snapshotDataset(snap)
+
+	if err := getVolume(dataset); err == nil {
+		logrus.Infof("using existing snapshot %v", dataset)
+		return nil
+	}
+
+	args := buildSnapshotCreateArgs(snap)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not create snapshot %v cmd %v error: %s", dataset, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("created snapshot %s", dataset)
+
+	return nil
+}
+
+// DestroySnapshot deletes the given ZFS snapshot. It is a no-op if the
+// snapshot is already gone, so a retried delete (e.g. a reconcile that
+// crashed after zfs destroy succeeded but before the finalizer was
+// removed) does not error out and block finalizer removal forever.
+func DestroySnapshot(snap *apis.ZFSSnapshot) error {
+	dataset := snapshotDataset(snap)
+
+	if err := getVolume(dataset); err != nil {
+		return nil
+	}
+
+	args := buildSnapshotDestroyArgs(snap)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not destroy snapshot %v cmd %v error: %s", dataset, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("destroyed snapshot %s", dataset)
+
+	return nil
+}
+
+// RollbackSnapshot rolls the backing volume back to the given ZFS snapshot,
+// discarding any changes made since the snapshot was taken
+func RollbackSnapshot(snap *apis.ZFSSnapshot) error {
+	dataset := snapshotDataset(snap)
+
+	args := buildSnapshotRollbackArgs(snap)
+	cmd := exec.Command(ZFSVolCmd, args...)
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		logrus.Errorf(
+			"zfs: could not rollback to snapshot %v cmd %v error: %s", dataset, args, string(out),
+		)
+		return err
+	}
+	logrus.Infof("rolled back to snapshot %s", dataset)
+
+	return nil
+}
+
+// CreateCloneFromSnapshot creates a new volume by cloning the ZFSSnapshot
+// named by vol.Spec.SnapshotName. The destination volume is described by
+// vol, which must have
+// vol.Spec.SnapshotName and vol.Spec.SnapshotVolumeName set to the name of
+// the snapshot being cloned and the volume it was taken from.
+func CreateCloneFromSnapshot(vol *apis.ZFSVolume, keyReader io.Reader) error {
+	return CreateVolume(vol, keyReader)
+}
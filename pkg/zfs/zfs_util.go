@@ -17,6 +17,7 @@ limitations under the License.
 package zfs
 
 import (
+	"io"
 	"os/exec"
 	"path/filepath"
 
@@ -32,11 +33,14 @@ const (
 
 // zfs command related constants
 const (
-	ZFSVolCmd     = "zfs"
-	ZFSCreateArg  = "create"
-	ZFSDestroyArg = "destroy"
-	ZFSSetArg     = "set"
-	ZFSListArg    = "list"
+	ZFSVolCmd      = "zfs"
+	ZFSCreateArg   = "create"
+	ZFSDestroyArg  = "destroy"
+	ZFSSetArg      = "set"
+	ZFSListArg     = "list"
+	ZFSSnapshotArg = "snapshot"
+	ZFSCloneArg    = "clone"
+	ZFSRollbackArg = "rollback"
 )
 
 // constants to define volume type
@@ -45,15 +49,18 @@ const (
 	VOLTYPE_ZVOL    = "ZVOL"
 )
 
-func PropertyChanged(oldVol *apis.ZFSVolume, newVol *apis.ZFSVolume) bool {
-	if oldVol.Spec.VolumeType == VOLTYPE_DATASET &&
-		newVol.Spec.VolumeType == VOLTYPE_DATASET &&
-		oldVol.Spec.RecordSize != newVol.Spec.RecordSize {
+// PropertyChanged compares the live properties of a volume, as reported by
+// GetVolumeProperties, against the desired state in vol.Spec and reports
+// whether any property zfs-localpv manages actually differs.
+func PropertyChanged(live *VolumeProperties, vol *apis.ZFSVolume) bool {
+	if vol.Spec.VolumeType == VOLTYPE_DATASET &&
+		len(vol.Spec.RecordSize) != 0 &&
+		live.RecordSize != vol.Spec.RecordSize {
 		return true
 	}
 
-	return oldVol.Spec.Compression != newVol.Spec.Compression ||
-		oldVol.Spec.Dedup != newVol.Spec.Dedup
+	return (len(vol.Spec.Compression) != 0 && live.Compression != vol.Spec.Compression) ||
+		(len(vol.Spec.Dedup) != 0 && live.Dedup != vol.Spec.Dedup)
 }
 
 // GetVolumeType returns the volume type
@@ -161,9 +168,10 @@ func buildDatasetCreateArgs(vol *apis.ZFSVolume) []string {
 	return ZFSVolArg
 }
 
-// builldVolumeSetArgs returns volume set command along with attributes as a string array
-// TODO(pawan) need to find a way to identify which property has changed
-func buildVolumeSetArgs(vol *apis.ZFSVolume) []string {
+// buildVolumeSetArgs returns the zfs set command that brings volume's live
+// properties in line with vol.Spec, setting only the properties that are
+// actually out of sync according to live.
+func buildVolumeSetArgs(vol *apis.ZFSVolume, live *VolumeProperties) []string {
 	var ZFSVolArg []string
 
 	volume := vol.Spec.PoolName + "/" + vol.Name
@@ -171,16 +179,17 @@ func buildVolumeSetArgs(vol *apis.ZFSVolume) []string {
 	ZFSVolArg = append(ZFSVolArg, ZFSSetArg)
 
 	if vol.Spec.VolumeType == VOLTYPE_DATASET &&
-		len(vol.Spec.RecordSize) != 0 {
+		len(vol.Spec.RecordSize) != 0 &&
+		live.RecordSize != vol.Spec.RecordSize {
 		recordsizeProperty := "recordsize=" + vol.Spec.RecordSize
 		ZFSVolArg = append(ZFSVolArg, recordsizeProperty)
 	}
 
-	if len(vol.Spec.Dedup) != 0 {
+	if len(vol.Spec.Dedup) != 0 && live.Dedup != vol.Spec.Dedup {
 		dedupProperty := "dedup=" + vol.Spec.Dedup
 		ZFSVolArg = append(ZFSVolArg, dedupProperty)
 	}
-	if len(vol.Spec.Compression) != 0 {
+	if len(vol.Spec.Compression) != 0 && live.Compression != vol.Spec.Compression {
 		compressionProperty := "compression=" + vol.Spec.Compression
 		ZFSVolArg = append(ZFSVolArg, compressionProperty)
 	}
@@ -202,23 +211,23 @@ func buildVolumeDestroyArgs(vol *apis.ZFSVolume) []string {
 }
 
 func getVolume(volume string) error {
-	var ZFSVolArg []string
-
-	ZFSVolArg = append(ZFSVolArg, ZFSListArg, volume)
-
-	cmd := exec.Command(ZFSVolCmd, ZFSVolArg...)
-	_, err := cmd.CombinedOutput()
+	_, err := GetVolumeProperties(volume)
 	return err
 }
 
-// CreateVolume creates the zvol/dataset as per
-// info provided in ZFSVolume object
-func CreateVolume(vol *apis.ZFSVolume) error {
+// CreateVolume creates the zvol/dataset as per info provided in the
+// ZFSVolume object. A fresh encryption root already has its key loaded by
+// zfs create itself, so keyReader is only consulted when an existing
+// KeyRef-backed volume's key is found unavailable (e.g. after a reboot);
+// keyReader may be nil when the volume is unencrypted.
+func CreateVolume(vol *apis.ZFSVolume, keyReader io.Reader) error {
 	volume := vol.Spec.PoolName + "/" + vol.Name
 
 	if err := getVolume(volume); err != nil {
 		var args []string
-		if vol.Spec.VolumeType == VOLTYPE_DATASET {
+		if len(vol.Spec.SnapshotName) != 0 {
+			args = buildCloneCreateArgs(vol)
+		} else if vol.Spec.VolumeType == VOLTYPE_DATASET {
 			args = buildDatasetCreateArgs(vol)
 		} else {
 			args = buildZvolCreateArgs(vol)
@@ -233,8 +242,24 @@ func CreateVolume(vol *apis.ZFSVolume) error {
 			return err
 		}
 		logrus.Infof("created volume %s", volume)
+
+		// A brand-new encryption root needs its key material to encrypt
+		// the dataset in the first place, so zfs create already leaves
+		// its key loaded; calling load-key here would just error.
 	} else if err == nil {
 		logrus.Infof("using existing volume %v", volume)
+
+		if vol.Spec.KeyRef != nil {
+			raw, err := GetVolumeProperties(volume)
+			if err != nil {
+				return err
+			}
+			if ParseVolumeProperties(raw).KeyStatus == "unavailable" {
+				if err := LoadKey(vol, keyReader); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return nil
@@ -270,33 +295,28 @@ func UmountZFSDataset(vol *apis.ZFSVolume) error {
 	return SetDatasetMountProp(volume, "none")
 }
 
-// SetZvolProp sets the volume property
+// SetZvolProp sets the volume properties that have drifted from vol.Spec.
+// Unlike a blind re-apply of every property on every reconcile, it fetches
+// the volume's live properties first and only issues zfs set for the ones
+// that actually differ, so a controller restart does not cause a spurious
+// rewrite of properties that never changed.
 func SetZvolProp(vol *apis.ZFSVolume) error {
 	var err error
 	volume := vol.Spec.PoolName + "/" + vol.Name
 
-	if len(vol.Spec.Compression) == 0 &&
-		len(vol.Spec.Dedup) == 0 &&
-		(vol.Spec.VolumeType != VOLTYPE_DATASET ||
-			len(vol.Spec.RecordSize) == 0) {
+	raw, err := GetVolumeProperties(volume)
+	if err != nil {
+		logrus.Errorf("zfs: could not get properties for volume %v error: %s", volume, err.Error())
+		return err
+	}
+	live := ParseVolumeProperties(raw)
+
+	if !PropertyChanged(live, vol) {
 		//nothing to set, just return
 		return nil
 	}
-	/* Case: Restart =>
-	 * In this case we get the add event but here we don't know which
-	 * property has changed when we were down, so firing the zfs set
-	 * command with the all property present on the ZFSVolume.
-
-	 * Case: Property Change =>
-	 * TODO(pawan) When we get the update event, we make sure at least
-	 * one property has changed before adding it to the event queue for
-	 * handling. At this stage, since we haven't stored the
-	 * ZFSVolume object as it will be too heavy, we are firing the set
-	 * command with the all property preset in the ZFSVolume object since
-	 * it is guaranteed that at least one property has changed.
-	 */
 
-	args := buildVolumeSetArgs(vol)
+	args := buildVolumeSetArgs(vol, live)
 	cmd := exec.Command(ZFSVolCmd, args...)
 	out, err := cmd.CombinedOutput()
 
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zfsbackup drives a ZFSBackup through its snapshot-chain
+// lifecycle: take a named snapshot, ship it (full or incremental) to the
+// configured BackupTarget, record the zfs diff against the previous backup
+// snapshot for observability, and garbage-collect snapshots that have
+// aged out of the retention window.
+package zfsbackup
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/openebs/zfs-localpv/pkg/backup"
+
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+	"github.com/openebs/zfs-localpv/pkg/zfs"
+)
+
+// snapshotName returns the name used for the backup snapshot taken at ts,
+// e.g. "backup-1580000000"
+func snapshotName(ts int64) string {
+	return fmt.Sprintf("backup-%d", ts)
+}
+
+// streamKey returns the object store key a backup snapshot's stream is
+// uploaded under
+func streamKey(backupObj *apis.ZFSBackup, snap string) string {
+	return backupObj.Namespace + "/" + backupObj.Name + "/" + snap
+}
+
+// RunBackup takes a new named snapshot of vol and ships it to target. By
+// default it sends an incremental stream relative to
+// backupObj.Status.LastSnapshot and appends the new snapshot to the
+// existing chain. When forceFull is true — typically because the chain has
+// grown past Spec.RetentionCount and needs rebasing — it instead sends a
+// full stream and resets the chain to start from this snapshot alone. The
+// caller is then responsible for passing the superseded chain to
+// GCSnapshots once this new full backup has landed, since nothing still
+// depends on those old snapshots/streams after the rebase.
+func RunBackup(backupObj *apis.ZFSBackup, vol *apis.ZFSVolume, target backup.BackupTarget, now int64, forceFull bool) (*apis.ZFSBackupStatus, error) {
+	snap := snapshotName(now)
+	baseSnap := backupObj.Status.LastSnapshot
+	chain := backupObj.Status.SnapshotChain
+	if forceFull {
+		baseSnap = ""
+		chain = nil
+	}
+
+	snapObj := &apis.ZFSSnapshot{}
+	snapObj.Name = snap
+	snapObj.Spec.VolumeName = backupObj.Spec.VolumeName
+	snapObj.Spec.PoolName = backupObj.Spec.PoolName
+	snapObj.Spec.OwnerNodeID = backupObj.Spec.OwnerNodeID
+
+	if err := zfs.CreateSnapshot(snapObj); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	sendErrCh := make(chan error, 1)
+	go func() {
+		err := zfs.SendVolume(vol, baseSnap, snap, pw)
+		sendErrCh <- err
+		// CloseWithError(nil) behaves like Close(): a clean EOF. A non-nil
+		// err is surfaced to the reader so a mid-stream send failure fails
+		// the upload instead of it seeing a clean EOF and parking a
+		// truncated stream in the backup target.
+		pw.CloseWithError(err)
+	}()
+
+	uploadErr := target.Upload(streamKey(backupObj, snap), pr)
+	// Drain/close pr even on an upload error so the SendVolume goroutine's
+	// `zfs send` child is never left blocked writing to a dead pipe.
+	pr.CloseWithError(uploadErr)
+	sendErr := <-sendErrCh
+
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	status := &apis.ZFSBackupStatus{
+		State:         "Synced",
+		LastSnapshot:  snap,
+		SnapshotChain: append(append([]string{}, chain...), snap),
+	}
+
+	if len(baseSnap) != 0 {
+		diff, err := zfs.GetSnapshotDiff(vol, baseSnap, snap)
+		if err != nil {
+			logrus.Errorf("zfsbackup: could not diff %s..%s for backup %s: %s", baseSnap, snap, backupObj.Name, err.Error())
+		} else {
+			status.ChangedPaths = diff.ChangedPaths
+			status.ChangedBytes = diff.ChangedBytes
+		}
+	}
+
+	logrus.Infof("zfsbackup: synced snapshot %s for backup %s", snap, backupObj.Name)
+
+	return status, nil
+}
+
+// GCSnapshots destroys obsoleteSnapshots of vol and removes their streams
+// from target. obsoleteSnapshots must be snapshots that a prior
+// RunBackup(forceFull=true) call has already rebased backupObj.Status.
+// SnapshotChain away from — never snapshots sliced out of the live chain by
+// Spec.RetentionCount here, since RunRestore replays the whole live chain
+// from its full-send anchor and cannot tolerate a gap in it.
+// DestroyBackupSnapshot is a no-op on an already-destroyed snapshot, so
+// GCSnapshots keeps going on a per-snapshot error instead of aborting the
+// whole pass, and is safe to call again with the same obsoleteSnapshots on
+// the next reconcile.
+func GCSnapshots(backupObj *apis.ZFSBackup, vol *apis.ZFSVolume, target backup.BackupTarget, obsoleteSnapshots []string) error {
+	var lastErr error
+	for _, snap := range obsoleteSnapshots {
+		if err := zfs.DestroyBackupSnapshot(vol, snap); err != nil {
+			logrus.Errorf("zfsbackup: could not destroy backup snapshot %s for backup %s: %s", snap, backupObj.Name, err.Error())
+			lastErr = err
+			continue
+		}
+		if err := target.Delete(streamKey(backupObj, snap)); err != nil {
+			logrus.Errorf("zfsbackup: could not delete stored stream for backup snapshot %s for backup %s: %s", snap, backupObj.Name, err.Error())
+			lastErr = err
+			continue
+		}
+		logrus.Infof("zfsbackup: garbage collected backup snapshot %s for backup %s", snap, backupObj.Name)
+	}
+
+	return lastErr
+}
+
+// RunRestore reconstructs vol on this node by downloading backupObj's
+// snapshot streams from target and piping them into zfs receive in order.
+// Every entry after the first in backupObj.Status.SnapshotChain is only an
+// incremental stream relative to its predecessor, so the whole chain must
+// be replayed from the initial full send to reconstruct the volume.
+func RunRestore(restoreObj *apis.ZFSRestore, backupObj *apis.ZFSBackup, vol *apis.ZFSVolume, target backup.BackupTarget) error {
+	for _, snap := range backupObj.Status.SnapshotChain {
+		stream, err := target.Download(streamKey(backupObj, snap))
+		if err != nil {
+			return err
+		}
+
+		err = zfs.ReceiveVolume(vol, stream)
+		stream.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	logrus.Infof("zfsbackup: restored volume %s from backup %s", vol.Name, backupObj.Name)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zfssnapshot reconciles ZFSSnapshot objects on the node that owns
+// the backing ZFSVolume, mirroring how the ZFSVolume controller reconciles
+// volumes on their owner node.
+package zfssnapshot
+
+import (
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+	"github.com/openebs/zfs-localpv/pkg/zfs"
+)
+
+// SnapshotController reconciles ZFSSnapshot objects owned by this node
+type SnapshotController struct {
+	// NodeID is the node this controller is running on; only ZFSSnapshot
+	// objects whose Spec.OwnerNodeID matches are reconciled here
+	NodeID string
+}
+
+// NewSnapshotController returns a SnapshotController for the given node
+func NewSnapshotController(nodeID string) *SnapshotController {
+	return &SnapshotController{NodeID: nodeID}
+}
+
+// SyncSnapshot reconciles a single ZFSSnapshot. It creates the snapshot if
+// it does not exist yet and is owned by this node, and removes it on
+// deletion timestamp.
+func (c *SnapshotController) SyncSnapshot(snap *apis.ZFSSnapshot) error {
+	if snap.Spec.OwnerNodeID != c.NodeID {
+		return nil
+	}
+
+	if snap.DeletionTimestamp != nil {
+		if err := zfs.DestroySnapshot(snap); err != nil {
+			logrus.Errorf("zfssnapshot: failed to destroy snapshot %s: %s", snap.Name, err.Error())
+			return err
+		}
+		return nil
+	}
+
+	// CreateSnapshot checks the live dataset itself and is a no-op if the
+	// snapshot already exists, so reconciling an already-synced object
+	// repeatedly does not error out trying to recreate it.
+	if err := zfs.CreateSnapshot(snap); err != nil {
+		logrus.Errorf("zfssnapshot: failed to create snapshot %s: %s", snap.Name, err.Error())
+		return err
+	}
+	snap.Status.State = "Ready"
+
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zfsvolumemigration drives a ZFSVolumeMigration through its
+// snapshot-and-stream lifecycle on the node plugin: an initial full `zfs
+// send`, followed by a fixed number of incremental sends, after which
+// Cutover rewrites the volume's ownerNodeID to the destination node.
+package zfsvolumemigration
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	apis "github.com/openebs/zfs-localpv/pkg/apis/openebs.io/core/v1alpha1"
+	"github.com/openebs/zfs-localpv/pkg/zfs"
+)
+
+// Transport opens a stream to the destination node's agent for a given
+// migration, used to carry `zfs send` output across the network.
+type Transport interface {
+	// OpenSendStream returns a writer that forwards bytes written to it
+	// to the destination node's ReceiveVolume call for the migration.
+	OpenSendStream(mig *apis.ZFSVolumeMigration) (io.WriteCloser, error)
+}
+
+// snapshotName returns the name used for the migration snapshot taken at
+// the given step, e.g. "migrate-<name>-3"
+func snapshotName(mig *apis.ZFSVolumeMigration, step int) string {
+	return fmt.Sprintf("migrate-%s-%d", mig.Name, step)
+}
+
+// RunMigration drives mig from Pending through to the incremental sync
+// loop, taking an initial snapshot and full send, then a bounded number of
+// incremental snapshots and sends, before the caller calls Cutover to
+// rewrite the volume's ownerNodeID to mig.Spec.DestNodeID. mig.Status.Phase
+// tracks progress so a watcher (or a resumed reconcile) can tell a stalled
+// migration from a failed one, and mig.Status.LastSyncedSnapshot records
+// the base for the next incremental send.
+func RunMigration(mig *apis.ZFSVolumeMigration, vol *apis.ZFSVolume, transport Transport, maxIncrementalRounds int) error {
+	mig.Status.Phase = apis.MigrationPending
+	baseSnap := mig.Status.LastSyncedSnapshot
+
+	for step := 0; step <= maxIncrementalRounds; step++ {
+		snap := snapshotName(mig, step)
+
+		if step == 0 {
+			mig.Status.Phase = apis.MigrationFullSync
+		} else {
+			mig.Status.Phase = apis.MigrationIncremental
+		}
+
+		snapObj := &apis.ZFSSnapshot{}
+		snapObj.Name = snap
+		snapObj.Spec.VolumeName = mig.Spec.VolumeName
+		snapObj.Spec.PoolName = mig.Spec.PoolName
+		snapObj.Spec.OwnerNodeID = mig.Spec.SourceNodeID
+
+		if err := zfs.CreateSnapshot(snapObj); err != nil {
+			mig.Status.Phase = apis.MigrationFailed
+			return err
+		}
+
+		stream, err := transport.OpenSendStream(mig)
+		if err != nil {
+			mig.Status.Phase = apis.MigrationFailed
+			return err
+		}
+
+		err = zfs.SendVolume(vol, baseSnap, snap, stream)
+		closeErr := stream.Close()
+		if err == nil {
+			// A buffered/network WriteCloser can fail its final flush only
+			// at Close() time, after SendVolume already reported success
+			// locally; that failure means the stream never actually landed
+			// on the destination node, so it must not be treated as synced.
+			err = closeErr
+		}
+		if err != nil {
+			mig.Status.Phase = apis.MigrationFailed
+			return err
+		}
+
+		mig.Status.LastSyncedSnapshot = snap
+		baseSnap = snap
+
+		logrus.Infof("zfsvolumemigration: synced snapshot %s (step %d) for migration %s", snap, step, mig.Name)
+	}
+
+	return nil
+}
+
+// Cutover rewrites vol's ownerNodeID to the migration's destination node
+// and marks the migration done. It does not check that RunMigration has
+// completed; it is called after RunMigration returns without error.
+func Cutover(mig *apis.ZFSVolumeMigration, vol *apis.ZFSVolume) {
+	mig.Status.Phase = apis.MigrationCutover
+
+	vol.Spec.OwnerNodeID = mig.Spec.DestNodeID
+	mig.Status.Phase = apis.MigrationDone
+
+	logrus.Infof("zfsvolumemigration: cut over volume %s to node %s", vol.Name, mig.Spec.DestNodeID)
+}